@@ -0,0 +1,209 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HealingStrategy identifica a ação de autocura que deve ser aplicada ao
+// Target quando a TriggerCondition é observada.
+type HealingStrategy string
+
+const (
+	// StrategyRestartPod reinicia o(s) pod(s) do target (delete, deixando o
+	// controller dono recriá-los)
+	StrategyRestartPod HealingStrategy = "RestartPod"
+
+	// StrategyScaleZero escala o target para zero réplicas
+	StrategyScaleZero HealingStrategy = "ScaleZero"
+
+	// StrategyRollback sintetiza um Rollback CR (rollback.autocura.io) para
+	// reverter o target para uma revisão anterior
+	StrategyRollback HealingStrategy = "Rollback"
+
+	// StrategyHelmRollback reverte uma release Helm para uma revisão anterior
+	StrategyHelmRollback HealingStrategy = "HelmRollback"
+)
+
+// HealingTarget identifica o recurso observado/curado por este Healing
+type HealingTarget struct {
+	// Kind é o tipo do recurso alvo (Deployment, StatefulSet, DaemonSet)
+	Kind string `json:"kind"`
+
+	// Name é o nome do recurso alvo
+	Name string `json:"name"`
+
+	// Namespace é o namespace do recurso alvo
+	Namespace string `json:"namespace"`
+}
+
+// TriggerCondition descreve o sinal de "não saudável" que dispara a
+// Strategy. Os dois campos são independentes: qualquer um que seja
+// ultrapassado dispara a estratégia.
+type TriggerCondition struct {
+	// PodCrashLoopThreshold dispara a estratégia quando algum pod do target
+	// acumula mais reinícios (somados entre seus containers) que este valor;
+	// o estado CrashLoopBackOff em si não é verificado
+	PodCrashLoopThreshold int32 `json:"podCrashLoopThreshold,omitempty"`
+
+	// NotReadySeconds dispara a estratégia quando o target permanece com a
+	// condição Ready/ContainersReady em false por mais que este período
+	NotReadySeconds int32 `json:"notReadySeconds,omitempty"`
+}
+
+// HelmRollbackSpec configura StrategyHelmRollback
+type HelmRollbackSpec struct {
+	// ReleaseName é o nome da release Helm a ser revertida
+	ReleaseName string `json:"releaseName"`
+
+	// Wait aguarda os recursos da release ficarem prontos antes de retornar
+	Wait bool `json:"wait,omitempty"`
+
+	// Force força a recriação de recursos que não podem ser atualizados via patch
+	Force bool `json:"force,omitempty"`
+
+	// CleanupOnFail remove novos recursos criados durante o rollback que falhou
+	CleanupOnFail bool `json:"cleanupOnFail,omitempty"`
+}
+
+// HealingSpec define o estado desejado do Healing
+type HealingSpec struct {
+	// Strategy é a ação de autocura a ser aplicada
+	Strategy HealingStrategy `json:"strategy"`
+
+	// Target é o recurso observado/curado por este Healing
+	Target HealingTarget `json:"target"`
+
+	// TriggerCondition descreve o sinal que dispara Strategy
+	TriggerCondition TriggerCondition `json:"triggerCondition,omitempty"`
+
+	// TargetVersion é a revisão para a qual reverter quando Strategy é
+	// StrategyRollback. Se vazio, o controller resolve para a revisão
+	// imediatamente anterior à revisão corrente do Target.
+	TargetVersion string `json:"targetVersion,omitempty"`
+
+	// HelmRollback configura StrategyHelmRollback; obrigatório quando
+	// Strategy é StrategyHelmRollback
+	HelmRollback *HelmRollbackSpec `json:"helmRollback,omitempty"`
+
+	// Selector identifica os Pods/Deployments cujo estado alimenta
+	// HealingStatus.Pods/Deployments, ao estilo do ResourceBundleState do
+	// monitor ONAP (HealingStatus.Events é correlacionado via Target, não via
+	// Selector). Tipicamente igual ao selector do Target.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// PodStatus é o snapshot observado de um Pod coberto por HealingSpec.Selector
+type PodStatus struct {
+	// Name é o nome do pod
+	Name string `json:"name"`
+
+	// Phase é a fase corrente do pod (Running, Pending, etc)
+	Phase string `json:"phase"`
+
+	// Ready reflete a condição PodReady do pod
+	Ready bool `json:"ready"`
+
+	// RestartCount é a soma dos reinícios de todos os containers do pod
+	RestartCount int32 `json:"restartCount,omitempty"`
+}
+
+// DeploymentStatus é o snapshot observado de um Deployment coberto por
+// HealingSpec.Selector
+type DeploymentStatus struct {
+	// Name é o nome do deployment
+	Name string `json:"name"`
+
+	// Replicas é o número de réplicas desejadas
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas é o número de réplicas prontas
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// UpdatedReplicas é o número de réplicas já na revisão mais recente
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+}
+
+// EventRef é o snapshot observado de um Event relevante para o target
+type EventRef struct {
+	// Reason é o motivo reportado pelo Event
+	Reason string `json:"reason"`
+
+	// Message é a mensagem reportada pelo Event
+	Message string `json:"message"`
+
+	// LastTimestamp é a última vez que o Event foi observado
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty"`
+}
+
+// HealingStatus define o estado observado do Healing
+type HealingStatus struct {
+	// Phase representa a fase atual do healing
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdateTime é o timestamp da última atualização
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// Message contém informações sobre o estado atual
+	Message string `json:"message,omitempty"`
+
+	// FailureCount conta reconciles consecutivos terminados em PhaseFailed,
+	// usado para calcular o backoff exponencial de requeue
+	FailureCount int32 `json:"failureCount,omitempty"`
+
+	// RollbackRef referencia o Rollback CR filho sintetizado quando
+	// Strategy é StrategyRollback
+	RollbackRef string `json:"rollbackRef,omitempty"`
+
+	// NotReadySince marca desde quando o target é observado com Ready/
+	// ContainersReady em false continuamente; usado para avaliar
+	// TriggerCondition.NotReadySeconds. É zerado assim que o target volta a
+	// ficar pronto.
+	NotReadySince *metav1.Time `json:"notReadySince,omitempty"`
+
+	// Pods é o snapshot observado dos Pods cobertos por HealingSpec.Selector
+	Pods []PodStatus `json:"pods,omitempty"`
+
+	// Deployments é o snapshot observado dos Deployments cobertos por
+	// HealingSpec.Selector
+	Deployments []DeploymentStatus `json:"deployments,omitempty"`
+
+	// Events é o snapshot de todos os Events observados para o target
+	Events []EventRef `json:"events,omitempty"`
+}
+
+// Valores possíveis para HealingStatus.Phase
+const (
+	PhasePending    = "Pending"
+	PhaseInProgress = "InProgress"
+	PhaseSucceeded  = "Succeeded"
+	PhaseFailed     = "Failed"
+)
+
+// FinalizerName é o finalizer usado para impedir a remoção do Healing
+// enquanto a estratégia de cura ainda está em andamento.
+const FinalizerName = "healing.autocura.io/finalizer"
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// Healing é o Schema para a API healing
+type Healing struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HealingSpec   `json:"spec,omitempty"`
+	Status HealingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HealingList contém uma lista de Healing
+type HealingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Healing `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Healing{}, &HealingList{})
+}
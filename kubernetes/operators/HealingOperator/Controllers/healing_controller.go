@@ -4,12 +4,19 @@ import (
 	"context"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	healingv1 "HealingOperator/Api/V1"
+	rollbackv1 "RollbackOperator/Api/V1"
+	pkgreconcile "pkg/reconcile"
 )
 
 // HealingReconciler reconciles a Healing object
@@ -21,21 +28,172 @@ type HealingReconciler struct {
 //+kubebuilder:rbac:groups=healing.autocura-cognitiva.io,resources=healings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=healing.autocura-cognitiva.io,resources=healings/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=healing.autocura-cognitiva.io,resources=healings/finalizers,verbs=update
+//+kubebuilder:rbac:groups=rollback.autocura.io,resources=rollbacks,verbs=get;list;watch;create;patch
+//+kubebuilder:rbac:groups=rollback.autocura.io,resources=rollbacks/status,verbs=get;patch
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=apps,resources=replicasets;controllerrevisions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *HealingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
-	log.Info("Reconciliando objeto Healing", "namespace", req.Namespace, "name", req.Name)
 
-	// TODO(user): your logic here
+	var healing healingv1.Healing
+	if err := r.Get(ctx, req.NamespacedName, &healing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
 
-	return ctrl.Result{RequeueAfter: time.Minute}, nil
+	if !healing.DeletionTimestamp.IsZero() {
+		// Para StrategyRollback, aguardamos o Rollback filho convergir antes
+		// de liberar o finalizer: o filho tem seu próprio finalizer e bloqueia
+		// a remoção enquanto InProgress, então liberar o nosso cedo demais
+		// cascateia a exclusão (OwnerReference) e aborta a reversão em curso.
+		settled := true
+		if healing.Status.Phase == healingv1.PhaseInProgress &&
+			healing.Spec.Strategy == healingv1.StrategyRollback && healing.Status.RollbackRef != "" {
+			childPhase, err := r.rollbackChildPhase(ctx, &healing)
+			switch {
+			case apierrors.IsNotFound(err):
+				settled = true
+			case err != nil:
+				log.Error(err, "erro ao verificar convergência do rollback filho durante a remoção")
+				return ctrl.Result{}, err
+			default:
+				settled = childPhase == rollbackv1.PhaseSucceeded || childPhase == rollbackv1.PhaseFailed
+			}
+		}
+		if !settled {
+			log.Info("aguardando rollback filho convergir antes de remover o finalizer", "name", healing.Name)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		if _, err := pkgreconcile.ReleaseFinalizer(ctx, r.Client, &healing, healingv1.FinalizerName, true); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if added, err := pkgreconcile.EnsureFinalizer(ctx, r.Client, &healing, healingv1.FinalizerName); err != nil {
+		return ctrl.Result{}, err
+	} else if added {
+		// O Update que persiste o finalizer é filtrado por
+		// IgnoreMetadataOnlyUpdates, então não gera um novo evento: forçamos
+		// o requeue aqui para não deixar o CR órfão de reconciliação.
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := r.rebuildAggregatedStatus(ctx, &healing); err != nil {
+		log.Error(err, "falha ao atualizar o snapshot de estado observado")
+		return ctrl.Result{}, err
+	}
+
+	switch healing.Status.Phase {
+	case healingv1.PhaseSucceeded, healingv1.PhaseFailed:
+		// Uma fase terminal não é um sink definitivo: se o sinal agregado em
+		// HealingStatus (acabado de atualizar acima) voltar a ficar não
+		// saudável — uma nova crash loop após a cura anterior, por exemplo —
+		// reabrimos o ciclo em vez de ignorar o target para sempre. Sem
+		// TriggerCondition configurada não há sinal a reavaliar, e um Healing
+		// em PhaseSucceeded não é reaberto.
+		cond := healing.Spec.TriggerCondition
+		if healing.Status.Phase == healingv1.PhaseSucceeded && cond.PodCrashLoopThreshold == 0 && cond.NotReadySeconds == 0 {
+			return pkgreconcile.ResultForPhase(pkgreconcile.PhaseSucceeded, 0), nil
+		}
+		met, wait := triggerConditionMet(&healing)
+		if !met {
+			if wait > 0 {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+			return pkgreconcile.ResultForPhase(pkgreconcile.Phase(healing.Status.Phase), healing.Status.FailureCount), nil
+		}
+		log.Info("condição de disparo observada novamente após fase terminal; reabrindo ciclo de cura", "previousPhase", healing.Status.Phase)
+		if _, err := r.setPhase(ctx, &healing, healingv1.PhasePending, "condição de disparo observada novamente após fase terminal"); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+
+	case healingv1.PhaseInProgress:
+		if healing.Spec.Strategy != healingv1.StrategyRollback || healing.Status.RollbackRef == "" {
+			return pkgreconcile.ResultForPhase(pkgreconcile.PhaseInProgress, 0), nil
+		}
+		childPhase, err := r.rollbackChildPhase(ctx, &healing)
+		if err != nil {
+			return r.setPhase(ctx, &healing, healingv1.PhaseFailed, err.Error())
+		}
+		switch childPhase {
+		case rollbackv1.PhaseSucceeded:
+			return r.setPhase(ctx, &healing, healingv1.PhaseSucceeded, "rollback filho concluído com sucesso")
+		case rollbackv1.PhaseFailed:
+			return r.setPhase(ctx, &healing, healingv1.PhaseFailed, "rollback filho falhou")
+		default:
+			return pkgreconcile.ResultForPhase(pkgreconcile.PhaseInProgress, 0), nil
+		}
+
+	default: // "" ou Pending
+		if met, wait := triggerConditionMet(&healing); !met {
+			log.V(1).Info("triggerCondition ainda não observada, aguardando", "target", healing.Spec.Target)
+			if wait > 0 {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+			return ctrl.Result{}, nil
+		}
+		log.Info("disparando estratégia de cura", "strategy", healing.Spec.Strategy, "target", healing.Spec.Target)
+		async, err := r.dispatchStrategy(ctx, &healing)
+		if err != nil {
+			return r.setPhase(ctx, &healing, healingv1.PhaseFailed, err.Error())
+		}
+		if async {
+			return r.setPhase(ctx, &healing, healingv1.PhaseInProgress, "aguardando o rollback filho convergir")
+		}
+		return r.setPhase(ctx, &healing, healingv1.PhaseSucceeded, "estratégia de cura aplicada")
+	}
+}
+
+// setPhase atualiza HealingStatus.Phase/Message/LastUpdateTime via
+// pkgreconcile.PatchStatus e devolve o ctrl.Result apropriado para a nova
+// fase, incrementando FailureCount quando a fase é PhaseFailed.
+func (r *HealingReconciler) setPhase(ctx context.Context, healing *healingv1.Healing, phase, message string) (ctrl.Result, error) {
+	err := pkgreconcile.PatchStatus(ctx, r.Client, healing, func() {
+		healing.Status.Phase = phase
+		healing.Status.Message = message
+		healing.Status.LastUpdateTime = metav1.Now()
+		if phase == healingv1.PhaseFailed {
+			healing.Status.FailureCount++
+		} else {
+			healing.Status.FailureCount = 0
+		}
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return pkgreconcile.ResultForPhase(pkgreconcile.Phase(phase), healing.Status.FailureCount), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *HealingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Event{}, "involvedObject.kind", func(obj client.Object) []string {
+		return []string{obj.(*corev1.Event).InvolvedObject.Kind}
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Event{}, "involvedObject.name", func(obj client.Object) []string {
+		return []string{obj.(*corev1.Event).InvolvedObject.Name}
+	}); err != nil {
+		return err
+	}
+
+	forPredicate := pkgreconcile.WithResourceVersionLogging("healing", pkgreconcile.IgnoreMetadataOnlyUpdates())
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&healingv1.Healing{}).
+		For(&healingv1.Healing{}, builder.WithPredicates(forPredicate)).
+		Owns(&rollbackv1.Rollback{}).
+		Watches(&corev1.Pod{}, r.watchHandler(), builder.WithPredicates(readyTransitionPredicate{})).
+		Watches(&appsv1.Deployment{}, r.watchHandler(), builder.WithPredicates(readyTransitionPredicate{})).
+		Watches(&appsv1.StatefulSet{}, r.watchHandler(), builder.WithPredicates(pkgreconcile.StatefulSetReadyPredicate{})).
+		Watches(&corev1.Event{}, r.eventWatchHandler()).
 		Complete(r)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,56 @@
+package Controllers
+
+import (
+	"context"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	healingv1 "HealingOperator/Api/V1"
+)
+
+// dispatchHelmRollback reverte a release Helm configurada em
+// healing.Spec.HelmRollback para a revisão imediatamente anterior à
+// corrente, usando helm.sh/helm/v3/pkg/action diretamente (sem invocar o
+// binário helm).
+func (r *HealingReconciler) dispatchHelmRollback(ctx context.Context, healing *healingv1.Healing) error {
+	cfg := healing.Spec.HelmRollback
+	if cfg == nil {
+		return fmt.Errorf("healing %s/%s usa Strategy=HelmRollback mas não define helmRollback", healing.Namespace, healing.Name)
+	}
+
+	settings := cli.New()
+	settings.SetNamespace(healing.Spec.Target.Namespace)
+
+	helmDebugLog := helmDebugLogger(ctx)
+	actionConfig := new(action.Configuration)
+	if err := actionConfig.Init(settings.RESTClientGetter(), healing.Spec.Target.Namespace, "secrets", helmDebugLog); err != nil {
+		return fmt.Errorf("falha ao inicializar cliente helm: %w", err)
+	}
+
+	rollback := action.NewRollback(actionConfig)
+	// Version=0 instrui o Helm a reverter para a revisão imediatamente
+	// anterior à atual, equivalente a `helm rollback <release>` sem revisão.
+	rollback.Wait = cfg.Wait
+	rollback.Force = cfg.Force
+	rollback.CleanupOnFail = cfg.CleanupOnFail
+
+	if err := rollback.Run(cfg.ReleaseName); err != nil {
+		return fmt.Errorf("falha ao reverter release helm %s: %w", cfg.ReleaseName, err)
+	}
+	return nil
+}
+
+// helmDebugLogger adapta o logr.Logger do contexto de reconciliação para a
+// assinatura func(format string, v ...interface{}) exigida por
+// action.Configuration.Init, para logar o progresso interno do helm
+// (criação/espera de releases, etc) pelo mesmo logger estruturado usado no
+// resto do controller.
+func helmDebugLogger(ctx context.Context) func(format string, v ...interface{}) {
+	logger := log.FromContext(ctx)
+	return func(format string, v ...interface{}) {
+		logger.V(1).Info(fmt.Sprintf(format, v...))
+	}
+}
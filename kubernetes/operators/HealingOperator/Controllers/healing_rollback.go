@@ -0,0 +1,170 @@
+package Controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	healingv1 "HealingOperator/Api/V1"
+	rollbackv1 "RollbackOperator/Api/V1"
+	pkgreconcile "pkg/reconcile"
+)
+
+// dispatchRollback garante a existência de um Rollback CR para o target do
+// Healing, na mesma namespace, com TargetVersion resolvida (ou a revisão
+// anterior, quando healing.Spec.TargetVersion está vazio). O Healing se
+// torna dono do Rollback via OwnerReference, então Owns(&rollbackv1.Rollback{})
+// em SetupWithManager reenfileira o Healing quando o filho progride.
+func (r *HealingReconciler) dispatchRollback(ctx context.Context, healing *healingv1.Healing) (string, error) {
+	targetVersion := healing.Spec.TargetVersion
+	if targetVersion == "" {
+		resolved, err := r.previousRevision(ctx, healing.Spec.Target)
+		if err != nil {
+			return "", fmt.Errorf("falha ao resolver a revisão anterior de %s/%s: %w",
+				healing.Spec.Target.Namespace, healing.Spec.Target.Name, err)
+		}
+		targetVersion = resolved
+	}
+
+	rollbackName := healing.Name
+	var existing rollbackv1.Rollback
+	err := r.Get(ctx, types.NamespacedName{Namespace: healing.Namespace, Name: rollbackName}, &existing)
+	switch {
+	case err == nil:
+		if existing.Status.Phase != rollbackv1.PhaseSucceeded && existing.Status.Phase != rollbackv1.PhaseFailed {
+			return existing.Name, nil
+		}
+		// O filho já convergiu (ou falhou) num ciclo anterior e esta Healing
+		// está sendo disparada de novo: reaplicamos a TargetVersion resolvida
+		// e reiniciamos a fase, senão rollbackChildPhase reportaria sucesso
+		// de uma reversão antiga sem executar nada.
+		original := existing.DeepCopy()
+		existing.Spec.TargetVersion = targetVersion
+		if err := r.Patch(ctx, &existing, client.MergeFrom(original)); err != nil {
+			return "", fmt.Errorf("falha ao atualizar Rollback %s: %w", rollbackName, err)
+		}
+		if err := pkgreconcile.PatchStatus(ctx, r.Client, &existing, func() {
+			existing.Status.Phase = rollbackv1.PhasePending
+			existing.Status.Message = ""
+			existing.Status.FailureCount = 0
+		}); err != nil {
+			return "", fmt.Errorf("falha ao reiniciar status do Rollback %s: %w", rollbackName, err)
+		}
+		return existing.Name, nil
+	case !apierrors.IsNotFound(err):
+		return "", err
+	}
+
+	rollback := &rollbackv1.Rollback{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rollbackName,
+			Namespace: healing.Namespace,
+		},
+		Spec: rollbackv1.RollbackSpec{
+			ResourceKind:      healing.Spec.Target.Kind,
+			ResourceName:      healing.Spec.Target.Name,
+			ResourceNamespace: healing.Spec.Target.Namespace,
+			TargetVersion:     targetVersion,
+		},
+	}
+	if err := controllerutil.SetControllerReference(healing, rollback, r.Scheme); err != nil {
+		return "", err
+	}
+	if err := r.Create(ctx, rollback); err != nil {
+		return "", fmt.Errorf("falha ao criar Rollback %s: %w", rollbackName, err)
+	}
+	return rollback.Name, nil
+}
+
+// rollbackChildPhase lê a fase atual do Rollback CR referenciado por
+// healing.Status.RollbackRef.
+func (r *HealingReconciler) rollbackChildPhase(ctx context.Context, healing *healingv1.Healing) (string, error) {
+	var rollback rollbackv1.Rollback
+	key := types.NamespacedName{Namespace: healing.Namespace, Name: healing.Status.RollbackRef}
+	if err := r.Get(ctx, key, &rollback); err != nil {
+		return "", err
+	}
+	return rollback.Status.Phase, nil
+}
+
+// previousRevision resolve a revisão imediatamente anterior à revisão
+// corrente do target, a partir do histórico de ReplicaSets (Deployment) ou
+// ControllerRevisions (StatefulSet/DaemonSet).
+func (r *HealingReconciler) previousRevision(ctx context.Context, target healingv1.HealingTarget) (string, error) {
+	switch target.Kind {
+	case "Deployment":
+		var deploy appsv1.Deployment
+		if err := r.Get(ctx, types.NamespacedName{Namespace: target.Namespace, Name: target.Name}, &deploy); err != nil {
+			return "", err
+		}
+		var rsList appsv1.ReplicaSetList
+		if err := r.List(ctx, &rsList, client.InNamespace(target.Namespace), client.MatchingLabels(deploy.Spec.Selector.MatchLabels)); err != nil {
+			return "", err
+		}
+		var revisions []int64
+		for i := range rsList.Items {
+			rs := &rsList.Items[i]
+			if !metav1.IsControlledBy(rs, &deploy) {
+				continue
+			}
+			if v, err := strconv.ParseInt(rs.Annotations["deployment.kubernetes.io/revision"], 10, 64); err == nil {
+				revisions = append(revisions, v)
+			}
+		}
+		return secondHighest(revisions)
+
+	case "StatefulSet", "DaemonSet":
+		var selector *metav1.LabelSelector
+		var owner client.Object
+		if target.Kind == "StatefulSet" {
+			var sts appsv1.StatefulSet
+			if err := r.Get(ctx, types.NamespacedName{Namespace: target.Namespace, Name: target.Name}, &sts); err != nil {
+				return "", err
+			}
+			selector = sts.Spec.Selector
+			owner = &sts
+		} else {
+			var ds appsv1.DaemonSet
+			if err := r.Get(ctx, types.NamespacedName{Namespace: target.Namespace, Name: target.Name}, &ds); err != nil {
+				return "", err
+			}
+			selector = ds.Spec.Selector
+			owner = &ds
+		}
+
+		var revisionList appsv1.ControllerRevisionList
+		if err := r.List(ctx, &revisionList, client.InNamespace(target.Namespace), client.MatchingLabels(selector.MatchLabels)); err != nil {
+			return "", err
+		}
+		revisions := make([]int64, 0, len(revisionList.Items))
+		for i := range revisionList.Items {
+			rev := &revisionList.Items[i]
+			if !metav1.IsControlledBy(rev, owner) {
+				continue
+			}
+			revisions = append(revisions, rev.Revision)
+		}
+		return secondHighest(revisions)
+
+	default:
+		return "", fmt.Errorf("tipo de recurso não suportado para resolução de revisão: %s", target.Kind)
+	}
+}
+
+// secondHighest retorna, como string, o segundo maior valor de revisions
+// (ou seja, a revisão imediatamente anterior à corrente).
+func secondHighest(revisions []int64) (string, error) {
+	if len(revisions) < 2 {
+		return "", fmt.Errorf("histórico de revisões insuficiente para resolver a revisão anterior")
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] > revisions[j] })
+	return strconv.FormatInt(revisions[1], 10), nil
+}
@@ -0,0 +1,51 @@
+package Controllers
+
+import "testing"
+
+func TestSecondHighest(t *testing.T) {
+	tests := []struct {
+		name      string
+		revisions []int64
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "ordem decrescente",
+			revisions: []int64{3, 2, 1},
+			want:      "2",
+		},
+		{
+			name:      "ordem crescente",
+			revisions: []int64{1, 2, 3},
+			want:      "2",
+		},
+		{
+			name:      "histórico com só uma revisão",
+			revisions: []int64{1},
+			wantErr:   true,
+		},
+		{
+			name:      "histórico vazio",
+			revisions: nil,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := secondHighest(tt.revisions)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("secondHighest(%v): esperava erro, não houve", tt.revisions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("secondHighest(%v): erro inesperado: %v", tt.revisions, err)
+			}
+			if got != tt.want {
+				t.Fatalf("secondHighest(%v) = %q, want %q", tt.revisions, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package Controllers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	healingv1 "HealingOperator/Api/V1"
+	pkgreconcile "pkg/reconcile"
+)
+
+// involvedObjectEvents lista os Events cujo InvolvedObject aponta para o
+// recurso informado. Events não herdam os labels do objeto que descrevem —
+// eles se correlacionam via involvedObject.{kind,name,namespace} — então não
+// dá para filtrá-los com o mesmo client.MatchingLabels usado para Pods e
+// Deployments.
+func involvedObjectEvents(ctx context.Context, c client.Client, namespace, kind, name string) ([]corev1.Event, error) {
+	var events corev1.EventList
+	if err := c.List(ctx, &events, client.InNamespace(namespace), client.MatchingFields{
+		"involvedObject.kind": kind,
+		"involvedObject.name": name,
+	}); err != nil {
+		return nil, err
+	}
+	return events.Items, nil
+}
+
+// rebuildAggregatedStatus relista, a partir do cache do manager, os
+// Pods/Deployments que casam com HealingSpec.Selector e os Events cujo
+// involvedObject é HealingSpec.Target, e grava o snapshot resultante em
+// HealingStatus.Pods/Deployments/Events — ao estilo
+// do ResourceBundleState do monitor ONAP, sem depender de scraping externo
+// de métricas.
+func (r *HealingReconciler) rebuildAggregatedStatus(ctx context.Context, healing *healingv1.Healing) error {
+	if healing.Spec.Selector == nil {
+		return nil
+	}
+	matchLabels := client.MatchingLabels(healing.Spec.Selector.MatchLabels)
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(healing.Namespace), matchLabels); err != nil {
+		return err
+	}
+	podStatuses := make([]healingv1.PodStatus, 0, len(pods.Items))
+	for i := range pods.Items {
+		podStatuses = append(podStatuses, toPodStatus(&pods.Items[i]))
+	}
+
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments, client.InNamespace(healing.Namespace), matchLabels); err != nil {
+		return err
+	}
+	deployStatuses := make([]healingv1.DeploymentStatus, 0, len(deployments.Items))
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		deployStatuses = append(deployStatuses, healingv1.DeploymentStatus{
+			Name:            d.Name,
+			Replicas:        d.Status.Replicas,
+			ReadyReplicas:   d.Status.ReadyReplicas,
+			UpdatedReplicas: d.Status.UpdatedReplicas,
+		})
+	}
+
+	events, err := involvedObjectEvents(ctx, r.Client, healing.Namespace, healing.Spec.Target.Kind, healing.Spec.Target.Name)
+	if err != nil {
+		return err
+	}
+	eventRefs := make([]healingv1.EventRef, 0, len(events))
+	for i := range events {
+		e := &events[i]
+		eventRefs = append(eventRefs, healingv1.EventRef{
+			Reason:        e.Reason,
+			Message:       e.Message,
+			LastTimestamp: e.LastTimestamp,
+		})
+	}
+
+	allReady := true
+	for _, pod := range podStatuses {
+		if !pod.Ready {
+			allReady = false
+			break
+		}
+	}
+
+	return pkgreconcile.PatchStatus(ctx, r.Client, healing, func() {
+		healing.Status.Pods = podStatuses
+		healing.Status.Deployments = deployStatuses
+		healing.Status.Events = eventRefs
+
+		if allReady {
+			healing.Status.NotReadySince = nil
+		} else if healing.Status.NotReadySince == nil {
+			now := metav1.Now()
+			healing.Status.NotReadySince = &now
+		}
+	})
+}
+
+func toPodStatus(pod *corev1.Pod) healingv1.PodStatus {
+	ready := false
+	var restarts int32
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			ready = true
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	return healingv1.PodStatus{
+		Name:         pod.Name,
+		Phase:        string(pod.Status.Phase),
+		Ready:        ready,
+		RestartCount: restarts,
+	}
+}
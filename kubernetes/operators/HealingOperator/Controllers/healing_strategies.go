@@ -0,0 +1,129 @@
+package Controllers
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	healingv1 "HealingOperator/Api/V1"
+	pkgreconcile "pkg/reconcile"
+)
+
+// dispatchStrategy executa healing.Spec.Strategy uma única vez. Para
+// StrategyRollback, o trabalho real é assíncrono (delegado a um Rollback
+// CR filho) e o retorno async=true sinaliza ao chamador para permanecer em
+// PhaseInProgress aguardando o filho; as demais estratégias são síncronas.
+func (r *HealingReconciler) dispatchStrategy(ctx context.Context, healing *healingv1.Healing) (async bool, err error) {
+	switch healing.Spec.Strategy {
+	case healingv1.StrategyRestartPod:
+		return false, r.dispatchRestartPod(ctx, healing.Spec.Target)
+
+	case healingv1.StrategyScaleZero:
+		return false, r.dispatchScaleZero(ctx, healing.Spec.Target)
+
+	case healingv1.StrategyRollback:
+		name, err := r.dispatchRollback(ctx, healing)
+		if err != nil {
+			return false, err
+		}
+		// Persistido num patch próprio: setPhase calcula o merge patch a partir
+		// do objeto já mutado, então uma atribuição em memória se perderia.
+		if err := pkgreconcile.PatchStatus(ctx, r.Client, healing, func() {
+			healing.Status.RollbackRef = name
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+
+	case healingv1.StrategyHelmRollback:
+		return false, r.dispatchHelmRollback(ctx, healing)
+
+	default:
+		return false, fmt.Errorf("estratégia de cura não suportada: %s", healing.Spec.Strategy)
+	}
+}
+
+// dispatchRestartPod apaga os pods do target, deixando o controller dono
+// (Deployment/StatefulSet/DaemonSet) recriá-los.
+func (r *HealingReconciler) dispatchRestartPod(ctx context.Context, target healingv1.HealingTarget) error {
+	selector, err := r.targetSelector(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(target.Namespace), client.MatchingLabels(selector)); err != nil {
+		return fmt.Errorf("falha ao listar pods de %s/%s: %w", target.Namespace, target.Name, err)
+	}
+	for i := range pods.Items {
+		if err := r.Delete(ctx, &pods.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("falha ao apagar pod %s: %w", pods.Items[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// dispatchScaleZero escala o target (Deployment/StatefulSet) para zero réplicas
+func (r *HealingReconciler) dispatchScaleZero(ctx context.Context, target healingv1.HealingTarget) error {
+	var zero int32
+	key := types.NamespacedName{Namespace: target.Namespace, Name: target.Name}
+
+	switch target.Kind {
+	case "Deployment":
+		var deploy appsv1.Deployment
+		if err := r.Get(ctx, key, &deploy); err != nil {
+			return err
+		}
+		original := deploy.DeepCopy()
+		deploy.Spec.Replicas = &zero
+		return r.Patch(ctx, &deploy, client.MergeFrom(original))
+
+	case "StatefulSet":
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, key, &sts); err != nil {
+			return err
+		}
+		original := sts.DeepCopy()
+		sts.Spec.Replicas = &zero
+		return r.Patch(ctx, &sts, client.MergeFrom(original))
+
+	default:
+		return fmt.Errorf("StrategyScaleZero não suporta o tipo de recurso %s", target.Kind)
+	}
+}
+
+// targetSelector obtém o label selector do recurso alvo.
+func (r *HealingReconciler) targetSelector(ctx context.Context, target healingv1.HealingTarget) (map[string]string, error) {
+	key := types.NamespacedName{Namespace: target.Namespace, Name: target.Name}
+
+	switch target.Kind {
+	case "Deployment":
+		var deploy appsv1.Deployment
+		if err := r.Get(ctx, key, &deploy); err != nil {
+			return nil, err
+		}
+		return deploy.Spec.Selector.MatchLabels, nil
+
+	case "StatefulSet":
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, key, &sts); err != nil {
+			return nil, err
+		}
+		return sts.Spec.Selector.MatchLabels, nil
+
+	case "DaemonSet":
+		var ds appsv1.DaemonSet
+		if err := r.Get(ctx, key, &ds); err != nil {
+			return nil, err
+		}
+		return ds.Spec.Selector.MatchLabels, nil
+
+	default:
+		return nil, fmt.Errorf("tipo de recurso não suportado: %s", target.Kind)
+	}
+}
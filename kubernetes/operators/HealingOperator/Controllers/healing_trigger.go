@@ -0,0 +1,43 @@
+package Controllers
+
+import (
+	"time"
+
+	healingv1 "HealingOperator/Api/V1"
+)
+
+// triggerConditionMet avalia HealingSpec.TriggerCondition contra o snapshot
+// agregado em HealingStatus (preenchido por rebuildAggregatedStatus na mesma
+// reconciliação). Quando TriggerCondition está zerada — nenhum dos dois
+// campos configurado — a estratégia dispara incondicionalmente, preservando
+// o comportamento de Healings que não declaram um gatilho explícito.
+//
+// Quando a condição ainda não foi atingida mas NotReadySeconds está em
+// contagem, o segundo retorno indica após quanto tempo reconciliar de novo
+// para reavaliar o threshold, já que nada mais vai acordar o reconciler
+// nesse meio tempo.
+func triggerConditionMet(healing *healingv1.Healing) (bool, time.Duration) {
+	cond := healing.Spec.TriggerCondition
+	if cond.PodCrashLoopThreshold == 0 && cond.NotReadySeconds == 0 {
+		return true, 0
+	}
+
+	if cond.PodCrashLoopThreshold > 0 {
+		for _, pod := range healing.Status.Pods {
+			if pod.RestartCount > cond.PodCrashLoopThreshold {
+				return true, 0
+			}
+		}
+	}
+
+	if cond.NotReadySeconds > 0 && healing.Status.NotReadySince != nil {
+		threshold := time.Duration(cond.NotReadySeconds) * time.Second
+		elapsed := time.Since(healing.Status.NotReadySince.Time)
+		if elapsed >= threshold {
+			return true, 0
+		}
+		return false, threshold - elapsed
+	}
+
+	return false, 0
+}
@@ -0,0 +1,104 @@
+package Controllers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	healingv1 "HealingOperator/Api/V1"
+)
+
+func TestTriggerConditionMet(t *testing.T) {
+	tests := []struct {
+		name     string
+		healing  healingv1.Healing
+		wantMet  bool
+		wantWait time.Duration // só checado quando wantMet é false
+	}{
+		{
+			name:    "sem condição configurada dispara imediatamente",
+			healing: healingv1.Healing{},
+			wantMet: true,
+		},
+		{
+			name: "crash loop abaixo do threshold não dispara",
+			healing: healingv1.Healing{
+				Spec: healingv1.HealingSpec{
+					TriggerCondition: healingv1.TriggerCondition{PodCrashLoopThreshold: 5},
+				},
+				Status: healingv1.HealingStatus{
+					Pods: []healingv1.PodStatus{{Name: "app-1", RestartCount: 3}},
+				},
+			},
+			wantMet: false,
+		},
+		{
+			name: "crash loop acima do threshold dispara",
+			healing: healingv1.Healing{
+				Spec: healingv1.HealingSpec{
+					TriggerCondition: healingv1.TriggerCondition{PodCrashLoopThreshold: 5},
+				},
+				Status: healingv1.HealingStatus{
+					Pods: []healingv1.PodStatus{{Name: "app-1", RestartCount: 6}},
+				},
+			},
+			wantMet: true,
+		},
+		{
+			name: "not ready há mais tempo que o threshold dispara",
+			healing: healingv1.Healing{
+				Spec: healingv1.HealingSpec{
+					TriggerCondition: healingv1.TriggerCondition{NotReadySeconds: 30},
+				},
+				Status: healingv1.HealingStatus{
+					NotReadySince: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				},
+			},
+			wantMet: true,
+		},
+		{
+			name: "not ready há menos tempo que o threshold aguarda o restante",
+			healing: healingv1.Healing{
+				Spec: healingv1.HealingSpec{
+					TriggerCondition: healingv1.TriggerCondition{NotReadySeconds: 30},
+				},
+				Status: healingv1.HealingStatus{
+					NotReadySince: &metav1.Time{Time: time.Now().Add(-10 * time.Second)},
+				},
+			},
+			wantMet:  false,
+			wantWait: 20 * time.Second,
+		},
+		{
+			name: "not ready configurado mas target nunca observado not ready",
+			healing: healingv1.Healing{
+				Spec: healingv1.HealingSpec{
+					TriggerCondition: healingv1.TriggerCondition{NotReadySeconds: 30},
+				},
+			},
+			wantMet: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			met, wait := triggerConditionMet(&tt.healing)
+			if met != tt.wantMet {
+				t.Fatalf("triggerConditionMet() met = %v, want %v", met, tt.wantMet)
+			}
+			if met {
+				return
+			}
+			// Margem de tolerância: o teste e triggerConditionMet calculam
+			// time.Since a partir de instantes ligeiramente diferentes.
+			delta := wait - tt.wantWait
+			if delta < 0 {
+				delta = -delta
+			}
+			if delta > time.Second {
+				t.Fatalf("triggerConditionMet() wait = %v, want ~%v", wait, tt.wantWait)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package Controllers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	healingv1 "HealingOperator/Api/V1"
+)
+
+// mapToMatchingHealings é o EnqueueRequestsFromMapFunc comum aos Watches de
+// Pod/Deployment/StatefulSet: lista todos os Healing CRs na namespace do objeto e
+// enfileira os que têm HealingSpec.Selector casando com seus labels.
+func (r *HealingReconciler) mapToMatchingHealings(ctx context.Context, obj client.Object) []reconcile.Request {
+	var healings healingv1.HealingList
+	if err := r.List(ctx, &healings, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range healings.Items {
+		h := &healings.Items[i]
+		if h.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(h.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: h.Namespace, Name: h.Name},
+		})
+	}
+	return requests
+}
+
+// mapEventToHealings é o EnqueueRequestsFromMapFunc do Watch de Event.
+// Diferente de Pod/Deployment, um Event não carrega os labels do objeto que
+// descreve — ele se correlaciona via involvedObject — então, em vez de casar
+// contra HealingSpec.Selector, casamos contra HealingSpec.Target, a mesma
+// correlação usada por involvedObjectEvents em rebuildAggregatedStatus.
+func (r *HealingReconciler) mapEventToHealings(ctx context.Context, obj client.Object) []reconcile.Request {
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return nil
+	}
+
+	var healings healingv1.HealingList
+	if err := r.List(ctx, &healings, client.InNamespace(ev.Namespace)); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range healings.Items {
+		h := &healings.Items[i]
+		if h.Spec.Target.Kind == ev.InvolvedObject.Kind && h.Spec.Target.Name == ev.InvolvedObject.Name {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: h.Namespace, Name: h.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// readyTransitionPredicate só deixa passar eventos de Pod/Deployment em que
+// as condições Ready/ContainersReady realmente mudaram de valor, em vez de a
+// cada heartbeat de status (ex.: LastHeartbeatTime sendo atualizado sem
+// mudança de estado).
+type readyTransitionPredicate struct {
+	predicate.Funcs
+}
+
+func (readyTransitionPredicate) Update(e event.UpdateEvent) bool {
+	return readySignature(e.ObjectOld) != readySignature(e.ObjectNew)
+}
+
+// readySignature resume as condições relevantes de Ready/ContainersReady (ou
+// o equivalente implícito de um Deployment) num valor comparável.
+func readySignature(obj client.Object) string {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		var sig string
+		for _, c := range o.Status.Conditions {
+			if c.Type == corev1.PodReady || c.Type == corev1.ContainersReady {
+				sig += "|" + string(c.Type) + "=" + string(c.Status)
+			}
+		}
+		return sig
+	case *appsv1.Deployment:
+		ready := o.Status.ReadyReplicas == o.Status.Replicas
+		return boolSignature(ready)
+	default:
+		return ""
+	}
+}
+
+func boolSignature(b bool) string {
+	if b {
+		return "ready"
+	}
+	return "not-ready"
+}
+
+// watchHandler é o handler.EventHandler usado pelos Watches de
+// Pod/Deployment/StatefulSet em SetupWithManager, para manter o construtor do
+// controller enxuto.
+func (r *HealingReconciler) watchHandler() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(r.mapToMatchingHealings)
+}
+
+// eventWatchHandler é o handler dedicado ao Watch de corev1.Event, que usa
+// mapEventToHealings em vez do mapeamento por label comum a Pod/Deployment.
+func (r *HealingReconciler) eventWatchHandler() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(r.mapEventToHealings)
+}
@@ -29,8 +29,31 @@ type RollbackStatus struct {
 
 	// Message contém informações sobre o estado atual
 	Message string `json:"message,omitempty"`
+
+	// FailureCount conta reconciles consecutivos terminados em PhaseFailed,
+	// usado para calcular o backoff exponencial de requeue
+	FailureCount int32 `json:"failureCount,omitempty"`
 }
 
+// Valores possíveis para RollbackStatus.Phase
+const (
+	// PhasePending indica que o rollback ainda não começou a ser processado
+	PhasePending = "Pending"
+
+	// PhaseInProgress indica que o rollback está em andamento
+	PhaseInProgress = "InProgress"
+
+	// PhaseSucceeded indica que o rollback foi concluído com sucesso
+	PhaseSucceeded = "Succeeded"
+
+	// PhaseFailed indica que o rollback falhou
+	PhaseFailed = "Failed"
+)
+
+// FinalizerName é o finalizer usado para impedir a remoção do Rollback
+// enquanto o workload alvo ainda não se estabilizou na versão revertida.
+const FinalizerName = "rollback.autocura.io/finalizer"
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -0,0 +1,38 @@
+package Controllers
+
+import (
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	rollbackv1 "RollbackOperator/Api/V1"
+)
+
+// RollbackCompletedPredicate descarta updates em Rollback CRs cuja fase
+// antiga já era PhaseSucceeded e cujo spec não mudou — um Rollback concluído
+// não tem mais trabalho a fazer, então só vale a pena reconciliar de novo se
+// alguém pedir um novo TargetVersion. Updates em que o Rollback está sendo
+// removido (deletionTimestamp setado) sempre passam, para que o finalizer seja
+// liberado.
+type RollbackCompletedPredicate struct {
+	predicate.Funcs
+}
+
+func (RollbackCompletedPredicate) Update(e event.UpdateEvent) bool {
+	old, ok := e.ObjectOld.(*rollbackv1.Rollback)
+	if !ok {
+		return true
+	}
+	new, ok := e.ObjectNew.(*rollbackv1.Rollback)
+	if !ok {
+		return true
+	}
+	if new.DeletionTimestamp != nil {
+		return true
+	}
+	if old.Status.Phase != rollbackv1.PhaseSucceeded {
+		return true
+	}
+	return !reflect.DeepEqual(old.Spec, new.Spec)
+}
@@ -2,40 +2,344 @@ package Controllers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	rollbackv1 "RollbackOperator/Api/V1"
+	"pkg/apply"
+	pkgreconcile "pkg/reconcile"
 )
 
 // RollbackReconciler reconciles a Rollback object
 type RollbackReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// ApplyConfigRef aponta para a ConfigMap de pkg/apply.ReconcileConfig que
+	// lista os campos que este operator possui em cada GVK. Quando zero,
+	// defaultApplyConfigRef é usado.
+	ApplyConfigRef types.NamespacedName
 }
 
+var defaultApplyConfigRef = types.NamespacedName{Namespace: "autocura-system", Name: "rollback-reconcile-config"}
+
 //+kubebuilder:rbac:groups=rollback.autocura.io,resources=rollbacks,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rollback.autocura.io,resources=rollbacks/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=rollback.autocura.io,resources=rollbacks/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=apps,resources=replicasets;controllerrevisions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *RollbackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
-	log.Info("Reconciliando objeto Rollback", "namespace", req.Namespace, "name", req.Name)
 
-	// TODO(user): your logic here
+	var rollback rollbackv1.Rollback
+	if err := r.Get(ctx, req.NamespacedName, &rollback); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Trata a remoção do CR: enquanto o rollback estiver em andamento,
+	// aguardamos o workload alvo se estabilizar antes de liberar o finalizer.
+	if !rollback.DeletionTimestamp.IsZero() {
+		settled := true
+		if rollback.Status.Phase == rollbackv1.PhaseInProgress {
+			var err error
+			settled, err = r.workloadSettled(ctx, rollback.Spec)
+			if err != nil {
+				log.Error(err, "erro ao verificar estabilização do workload durante a remoção")
+				return ctrl.Result{}, err
+			}
+		}
+		if !settled {
+			log.Info("aguardando workload se estabilizar antes de remover o finalizer", "name", rollback.Name)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		if _, err := pkgreconcile.ReleaseFinalizer(ctx, r.Client, &rollback, rollbackv1.FinalizerName, true); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if added, err := pkgreconcile.EnsureFinalizer(ctx, r.Client, &rollback, rollbackv1.FinalizerName); err != nil {
+		return ctrl.Result{}, err
+	} else if added {
+		// O Update que persiste o finalizer é filtrado por
+		// IgnoreMetadataOnlyUpdates, então não gera um novo evento: forçamos
+		// o requeue aqui para não deixar o CR órfão de reconciliação.
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	switch rollback.Status.Phase {
+	case rollbackv1.PhaseSucceeded:
+		return pkgreconcile.ResultForPhase(pkgreconcile.PhaseSucceeded, 0), nil
+
+	case rollbackv1.PhaseInProgress:
+		// já disparamos a reversão; só resta aguardar o workload se estabilizar.
+		settled, err := r.workloadSettled(ctx, rollback.Spec)
+		if err != nil {
+			return r.setPhase(ctx, &rollback, rollbackv1.PhaseFailed, err.Error())
+		}
+		if !settled {
+			return pkgreconcile.ResultForPhase(pkgreconcile.PhaseInProgress, 0), nil
+		}
+		return r.setPhase(ctx, &rollback, rollbackv1.PhaseSucceeded,
+			fmt.Sprintf("revertido para a revisão %s", rollback.Spec.TargetVersion))
+
+	default: // "", Pending, ou Failed — o backoff de PhaseFailed expirou e é
+		// hora de tentar a reversão de novo, em vez de apenas recontar o relógio.
+		log.Info("iniciando rollback", "kind", rollback.Spec.ResourceKind, "name", rollback.Spec.ResourceName, "targetVersion", rollback.Spec.TargetVersion)
+		if err := r.applyRollback(ctx, rollback.Spec); err != nil {
+			return r.setPhase(ctx, &rollback, rollbackv1.PhaseFailed, err.Error())
+		}
+		return r.setPhase(ctx, &rollback, rollbackv1.PhaseInProgress,
+			fmt.Sprintf("revisão %s aplicada, aguardando estabilização", rollback.Spec.TargetVersion))
+	}
+}
+
+// setPhase atualiza RollbackStatus.Phase/Message/LastUpdateTime via
+// pkgreconcile.PatchStatus e devolve o ctrl.Result apropriado para a nova
+// fase, incrementando FailureCount quando a fase é PhaseFailed.
+func (r *RollbackReconciler) setPhase(ctx context.Context, rollback *rollbackv1.Rollback, phase, message string) (ctrl.Result, error) {
+	err := pkgreconcile.PatchStatus(ctx, r.Client, rollback, func() {
+		rollback.Status.Phase = phase
+		rollback.Status.Message = message
+		rollback.Status.LastUpdateTime = metav1.Now()
+		if phase == rollbackv1.PhaseFailed {
+			rollback.Status.FailureCount++
+		} else {
+			rollback.Status.FailureCount = 0
+		}
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return pkgreconcile.ResultForPhase(pkgreconcile.Phase(phase), rollback.Status.FailureCount), nil
+}
+
+// applyRollback localiza a revisão alvo e reverte para ela os owned paths
+// (pkg/apply.ReconcileConfig) do pod template do workload. Diferente de
+// `kubectl rollout undo`, campos do template fora desses owned paths não são
+// revertidos.
+func (r *RollbackReconciler) applyRollback(ctx context.Context, spec rollbackv1.RollbackSpec) error {
+	switch spec.ResourceKind {
+	case "Deployment":
+		return r.rollbackDeployment(ctx, spec)
+	case "StatefulSet":
+		return r.rollbackStatefulSet(ctx, spec)
+	case "DaemonSet":
+		return r.rollbackDaemonSet(ctx, spec)
+	default:
+		return fmt.Errorf("tipo de recurso não suportado para rollback: %s", spec.ResourceKind)
+	}
+}
+
+func (r *RollbackReconciler) rollbackDeployment(ctx context.Context, spec rollbackv1.RollbackSpec) error {
+	var deploy appsv1.Deployment
+	if err := r.Get(ctx, types.NamespacedName{Namespace: spec.ResourceNamespace, Name: spec.ResourceName}, &deploy); err != nil {
+		return fmt.Errorf("falha ao obter Deployment %s/%s: %w", spec.ResourceNamespace, spec.ResourceName, err)
+	}
+
+	var rsList appsv1.ReplicaSetList
+	if err := r.List(ctx, &rsList, client.InNamespace(spec.ResourceNamespace), client.MatchingLabels(deploy.Spec.Selector.MatchLabels)); err != nil {
+		return fmt.Errorf("falha ao listar ReplicaSets do Deployment %s: %w", deploy.Name, err)
+	}
+
+	var target *appsv1.ReplicaSet
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		if !metav1.IsControlledBy(rs, &deploy) {
+			continue
+		}
+		if rs.Annotations["deployment.kubernetes.io/revision"] == spec.TargetVersion {
+			target = rs
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("nenhuma revisão %s encontrada no histórico do Deployment %s", spec.TargetVersion, deploy.Name)
+	}
+
+	cfg, err := r.applyConfigFor(ctx, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	if err != nil {
+		return err
+	}
+
+	desired := deploy.DeepCopy()
+	desired.Spec.Template = *target.Spec.Template.DeepCopy()
+	return apply.Apply(ctx, r.Client, desired, &deploy, cfg)
+}
+
+func (r *RollbackReconciler) rollbackStatefulSet(ctx context.Context, spec rollbackv1.RollbackSpec) error {
+	var sts appsv1.StatefulSet
+	if err := r.Get(ctx, types.NamespacedName{Namespace: spec.ResourceNamespace, Name: spec.ResourceName}, &sts); err != nil {
+		return fmt.Errorf("falha ao obter StatefulSet %s/%s: %w", spec.ResourceNamespace, spec.ResourceName, err)
+	}
+
+	revision, err := r.findControllerRevision(ctx, spec, sts.Spec.Selector, &sts)
+	if err != nil {
+		return err
+	}
+	template, err := podTemplateFromRevision(revision)
+	if err != nil {
+		return fmt.Errorf("falha ao decodificar ControllerRevision %s: %w", revision.Name, err)
+	}
+
+	cfg, err := r.applyConfigFor(ctx, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+	if err != nil {
+		return err
+	}
 
-	return ctrl.Result{RequeueAfter: time.Minute}, nil
+	desired := sts.DeepCopy()
+	desired.Spec.Template = *template
+	return apply.Apply(ctx, r.Client, desired, &sts, cfg)
+}
+
+func (r *RollbackReconciler) rollbackDaemonSet(ctx context.Context, spec rollbackv1.RollbackSpec) error {
+	var ds appsv1.DaemonSet
+	if err := r.Get(ctx, types.NamespacedName{Namespace: spec.ResourceNamespace, Name: spec.ResourceName}, &ds); err != nil {
+		return fmt.Errorf("falha ao obter DaemonSet %s/%s: %w", spec.ResourceNamespace, spec.ResourceName, err)
+	}
+
+	revision, err := r.findControllerRevision(ctx, spec, ds.Spec.Selector, &ds)
+	if err != nil {
+		return err
+	}
+	template, err := podTemplateFromRevision(revision)
+	if err != nil {
+		return fmt.Errorf("falha ao decodificar ControllerRevision %s: %w", revision.Name, err)
+	}
+
+	cfg, err := r.applyConfigFor(ctx, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+	if err != nil {
+		return err
+	}
+
+	desired := ds.DeepCopy()
+	desired.Spec.Template = *template
+	return apply.Apply(ctx, r.Client, desired, &ds, cfg)
+}
+
+// applyConfigFor resolve o pkg/apply.ReconcileConfig do gvk informado,
+// dando preferência à ConfigMap apontada por r.ApplyConfigRef e caindo para
+// apply.DefaultConfigs() quando o gvk não está coberto por ela.
+func (r *RollbackReconciler) applyConfigFor(ctx context.Context, gvk schema.GroupVersionKind) (apply.ReconcileConfig, error) {
+	configRef := r.ApplyConfigRef
+	if configRef == (types.NamespacedName{}) {
+		configRef = defaultApplyConfigRef
+	}
+	configs, err := apply.LoadConfigs(ctx, r.Client, configRef)
+	if err != nil {
+		return apply.ReconcileConfig{}, err
+	}
+	if cfg, ok := configs[gvk]; ok {
+		return cfg, nil
+	}
+	return apply.DefaultConfigs()[gvk], nil
+}
+
+// podTemplateFromRevision decodifica o PodTemplateSpec armazenado num
+// ControllerRevision de StatefulSet/DaemonSet. ControllerRevision.Data.Raw é
+// um patch estratégico serializado do objeto original — no formato
+// `{"spec":{"template":{...}}}` — e não o PodTemplateSpec isolado.
+func podTemplateFromRevision(revision *appsv1.ControllerRevision) (*corev1.PodTemplateSpec, error) {
+	var decoded struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(revision.Data.Raw, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded.Spec.Template, nil
+}
+
+// findControllerRevision localiza o ControllerRevision cujo Revision bate com
+// a TargetVersion solicitada, dentre os controlados pelo dono informado.
+func (r *RollbackReconciler) findControllerRevision(ctx context.Context, spec rollbackv1.RollbackSpec, selector *metav1.LabelSelector, owner client.Object) (*appsv1.ControllerRevision, error) {
+	targetRevision, err := strconv.ParseInt(spec.TargetVersion, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("targetVersion inválido %q para %s: %w", spec.TargetVersion, spec.ResourceKind, err)
+	}
+
+	var revisions appsv1.ControllerRevisionList
+	if err := r.List(ctx, &revisions, client.InNamespace(spec.ResourceNamespace), client.MatchingLabels(selector.MatchLabels)); err != nil {
+		return nil, fmt.Errorf("falha ao listar ControllerRevisions de %s: %w", spec.ResourceName, err)
+	}
+
+	for i := range revisions.Items {
+		rev := &revisions.Items[i]
+		if !metav1.IsControlledBy(rev, owner) {
+			continue
+		}
+		if rev.Revision == targetRevision {
+			return rev, nil
+		}
+	}
+	return nil, fmt.Errorf("nenhuma revisão %d encontrada no histórico de %s", targetRevision, spec.ResourceName)
+}
+
+// workloadSettled reporta se o workload alvo já convergiu para o estado
+// desejado após a reversão (todas as réplicas prontas e atualizadas).
+func (r *RollbackReconciler) workloadSettled(ctx context.Context, spec rollbackv1.RollbackSpec) (bool, error) {
+	key := types.NamespacedName{Namespace: spec.ResourceNamespace, Name: spec.ResourceName}
+
+	switch spec.ResourceKind {
+	case "Deployment":
+		var deploy appsv1.Deployment
+		if err := r.Get(ctx, key, &deploy); err != nil {
+			return false, err
+		}
+		return deploy.Status.UpdatedReplicas == *deploy.Spec.Replicas &&
+			deploy.Status.ReadyReplicas == *deploy.Spec.Replicas, nil
+
+	case "StatefulSet":
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, key, &sts); err != nil {
+			return false, err
+		}
+		return sts.Status.UpdatedReplicas == *sts.Spec.Replicas &&
+			sts.Status.ReadyReplicas == *sts.Spec.Replicas, nil
+
+	case "DaemonSet":
+		var ds appsv1.DaemonSet
+		if err := r.Get(ctx, key, &ds); err != nil {
+			return false, err
+		}
+		return ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled &&
+			ds.Status.NumberReady == ds.Status.DesiredNumberScheduled, nil
+
+	default:
+		return false, fmt.Errorf("tipo de recurso não suportado: %s", spec.ResourceKind)
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RollbackReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	watchPredicate := pkgreconcile.WithResourceVersionLogging("rollback",
+		predicate.And(pkgreconcile.IgnoreMetadataOnlyUpdates(), RollbackCompletedPredicate{}))
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&rollbackv1.Rollback{}).
+		For(&rollbackv1.Rollback{}, builder.WithPredicates(watchPredicate)).
 		Complete(r)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,41 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Apply projeta desired e live através de cfg.OwnedPaths e, se algum desses
+// campos divergir, emite um merge patch estratégico no servidor limitado a
+// eles — preservando qualquer campo fora de cfg.OwnedPaths que outro
+// controller (ou um admission webhook) tenha adicionado a live.
+func Apply(ctx context.Context, c client.Client, desired, live client.Object, cfg ReconcileConfig) error {
+	desiredMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return fmt.Errorf("apply: falha ao converter objeto desejado: %w", err)
+	}
+	liveMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return fmt.Errorf("apply: falha ao converter objeto ao vivo: %w", err)
+	}
+
+	patch, changed, err := BuildPatch(desiredMap, liveMap, cfg.OwnedPaths)
+	if err != nil {
+		return fmt.Errorf("apply: falha ao projetar owned paths: %w", err)
+	}
+	if !changed {
+		return nil
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("apply: falha ao serializar patch: %w", err)
+	}
+
+	return c.Patch(ctx, live, client.RawPatch(types.StrategicMergePatchType, data))
+}
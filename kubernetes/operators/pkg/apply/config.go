@@ -0,0 +1,93 @@
+// Package apply implementa um engine de apply "diff-aware": em vez de
+// sobrescrever um objeto inteiro, ele só compara e corrige os campos que o
+// operator efetivamente possui (OwnedPaths de um ReconcileConfig). Isso
+// evita que o rollback apague sidecars, env vars e tolerations injetados por
+// webhooks ou outros controllers, e evita o ping-pong de updates quando esse
+// outro controller reaplica sua própria mutação.
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReconcileConfig declara, para um GVK, quais caminhos do objeto o operator
+// considera "seus" — os únicos que ele está autorizado a sobrescrever numa
+// reversão. Caminhos seguem a notação JSONPath usada no resto do projeto,
+// ex.: "spec.template.spec.containers[*].image".
+type ReconcileConfig struct {
+	GVK        schema.GroupVersionKind `json:"gvk"`
+	OwnedPaths []string                `json:"ownedPaths"`
+}
+
+// DefaultConfigs são os ReconcileConfig usados quando nenhuma ConfigMap de
+// override é encontrada. Cobrem apenas o que o RollbackReconciler de fato
+// precisa reverter: a imagem e os recursos dos containers do pod template.
+// Caminhos de lista sem patchMergeKey (ex.: "spec.template.spec.tolerations")
+// não entram aqui de propósito: BuildPatch não sabe mesclá-los por elemento,
+// então um caminho largo como "spec.template" sobrescreveria essas listas por
+// inteiro e apagaria entradas adicionadas por outro controller ou webhook —
+// exatamente o que este pacote existe para evitar. Operadores que precisem
+// reverter mais campos devem declará-los explicitamente via ConfigMap.
+func DefaultConfigs() map[schema.GroupVersionKind]ReconcileConfig {
+	paths := []string{
+		"spec.template.spec.containers[*].image",
+		"spec.template.spec.containers[*].resources",
+	}
+	configs := map[schema.GroupVersionKind]ReconcileConfig{}
+	for _, kind := range []string{"Deployment", "StatefulSet", "DaemonSet"} {
+		gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: kind}
+		configs[gvk] = ReconcileConfig{GVK: gvk, OwnedPaths: paths}
+	}
+	return configs
+}
+
+// LoadConfigs lê um ConfigMap onde cada chave é um GVK no formato
+// "<group>/<version>/<kind>" (grupo vazio para o core, ex.: "/v1/Pod") e o
+// valor é a lista de caminhos possuídos, um por linha. Permite que
+// operadores estendam a cobertura sem rebuild. Se o ConfigMap não existir,
+// retorna DefaultConfigs().
+func LoadConfigs(ctx context.Context, c client.Client, key types.NamespacedName) (map[schema.GroupVersionKind]ReconcileConfig, error) {
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, key, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return DefaultConfigs(), nil
+		}
+		return nil, fmt.Errorf("falha ao carregar ConfigMap de ReconcileConfig %s: %w", key, err)
+	}
+
+	configs := make(map[schema.GroupVersionKind]ReconcileConfig, len(cm.Data))
+	for rawGVK, rawPaths := range cm.Data {
+		gvk, err := parseGVKKey(rawGVK)
+		if err != nil {
+			return nil, err
+		}
+		configs[gvk] = ReconcileConfig{GVK: gvk, OwnedPaths: splitNonEmptyLines(rawPaths)}
+	}
+	return configs, nil
+}
+
+func parseGVKKey(key string) (schema.GroupVersionKind, error) {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) != 3 {
+		return schema.GroupVersionKind{}, fmt.Errorf("chave de ConfigMap inválida, esperado <group>/<version>/<kind>: %q", key)
+	}
+	return schema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]}, nil
+}
+
+func splitNonEmptyLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
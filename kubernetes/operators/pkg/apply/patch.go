@@ -0,0 +1,188 @@
+package apply
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// pathSegment é um componente de um owned path, ex.: "containers" no path
+// "spec.template.spec.containers[*].image". wildcard marca um segmento de
+// lista que deve ser iterado (apenas "[*]" é suportado).
+type pathSegment struct {
+	field    string
+	wildcard bool
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$."), ".")
+	parts := strings.Split(path, ".")
+	segments := make([]pathSegment, 0, len(parts))
+	for _, p := range parts {
+		if idx := strings.Index(p, "["); idx >= 0 {
+			if !strings.HasSuffix(p, "[*]") {
+				return nil, fmt.Errorf("apply: apenas o wildcard [*] é suportado, recebido %q", path)
+			}
+			segments = append(segments, pathSegment{field: p[:idx], wildcard: true})
+			continue
+		}
+		segments = append(segments, pathSegment{field: p})
+	}
+	return segments, nil
+}
+
+func fieldNames(segments []pathSegment) []string {
+	names := make([]string, len(segments))
+	for i, s := range segments {
+		names[i] = s.field
+	}
+	return names
+}
+
+// listGroup acumula, para um mesmo caminho de lista (ex.:
+// spec.template.spec.containers), os sufixos de campo possuídos dentro de
+// cada elemento (ex.: "image", "resources").
+type listGroup struct {
+	listPath []string
+	suffixes []string
+}
+
+// BuildPatch projeta desiredObj e liveObj (como produzidos por
+// runtime.DefaultUnstructuredConverter.ToUnstructured) através de ownedPaths
+// e devolve um documento de merge patch contendo só os campos possuídos cujo
+// valor desejado diverge do observado ao vivo.
+func BuildPatch(desiredObj, liveObj map[string]interface{}, ownedPaths []string) (map[string]interface{}, bool, error) {
+	patch := map[string]interface{}{}
+	changed := false
+
+	groups := map[string]*listGroup{}
+	var plainPaths [][]string
+
+	for _, raw := range ownedPaths {
+		segments, err := parsePath(raw)
+		if err != nil {
+			return nil, false, err
+		}
+
+		wildcardIdx := -1
+		for i, s := range segments {
+			if s.wildcard {
+				wildcardIdx = i
+				break
+			}
+		}
+		if wildcardIdx == -1 {
+			plainPaths = append(plainPaths, fieldNames(segments))
+			continue
+		}
+
+		listPath := fieldNames(segments[:wildcardIdx+1])
+		key := strings.Join(listPath, ".")
+		g, ok := groups[key]
+		if !ok {
+			g = &listGroup{listPath: listPath}
+			groups[key] = g
+		}
+		g.suffixes = append(g.suffixes, strings.Join(fieldNames(segments[wildcardIdx+1:]), "."))
+	}
+
+	for _, g := range groups {
+		itemChanged, err := applyListGroup(patch, desiredObj, liveObj, g)
+		if err != nil {
+			return nil, false, err
+		}
+		changed = changed || itemChanged
+	}
+
+	for _, fields := range plainPaths {
+		desiredVal, found, err := unstructured.NestedFieldNoCopy(desiredObj, fields...)
+		if err != nil {
+			return nil, false, err
+		}
+		if !found {
+			continue
+		}
+		liveVal, _, err := unstructured.NestedFieldNoCopy(liveObj, fields...)
+		if err != nil {
+			return nil, false, err
+		}
+		if !reflect.DeepEqual(desiredVal, liveVal) {
+			changed = true
+		}
+		if err := unstructured.SetNestedField(patch, desiredVal, fields...); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return patch, changed, nil
+}
+
+// applyListGroup reconstrói a lista em g.listPath contendo, para cada item
+// (casado por "name" entre desired e live), só os campos de g.suffixes.
+// Outros campos do item (sidecars adicionados por webhooks, por exemplo,
+// aparecem como itens extras na lista live e nunca são tocados porque o
+// patch estratégico mescla por patchMergeKey "name").
+func applyListGroup(patch map[string]interface{}, desiredObj, liveObj map[string]interface{}, g *listGroup) (bool, error) {
+	desiredList, _, err := unstructured.NestedSlice(desiredObj, g.listPath...)
+	if err != nil {
+		return false, err
+	}
+	liveList, _, err := unstructured.NestedSlice(liveObj, g.listPath...)
+	if err != nil {
+		return false, err
+	}
+
+	liveByName := map[string]map[string]interface{}{}
+	for _, item := range liveList {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				liveByName[name] = m
+			}
+		}
+	}
+
+	changed := false
+	patchedItems := make([]interface{}, 0, len(desiredList))
+	for _, item := range desiredList {
+		desiredItem, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := desiredItem["name"].(string)
+		entry := map[string]interface{}{"name": name}
+		liveItem := liveByName[name]
+
+		for _, suffix := range g.suffixes {
+			fields := strings.Split(suffix, ".")
+			desiredVal, found, err := unstructured.NestedFieldNoCopy(desiredItem, fields...)
+			if err != nil {
+				return false, err
+			}
+			if !found {
+				continue
+			}
+			if err := unstructured.SetNestedField(entry, desiredVal, fields...); err != nil {
+				return false, err
+			}
+
+			var liveVal interface{}
+			if liveItem != nil {
+				liveVal, _, err = unstructured.NestedFieldNoCopy(liveItem, fields...)
+				if err != nil {
+					return false, err
+				}
+			}
+			if !reflect.DeepEqual(desiredVal, liveVal) {
+				changed = true
+			}
+		}
+		patchedItems = append(patchedItems, entry)
+	}
+
+	if err := unstructured.SetNestedSlice(patch, patchedItems, g.listPath...); err != nil {
+		return false, err
+	}
+	return changed, nil
+}
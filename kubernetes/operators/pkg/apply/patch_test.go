@@ -0,0 +1,175 @@
+package apply
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    []pathSegment
+		wantErr bool
+	}{
+		{
+			name: "plain",
+			path: "spec.replicas",
+			want: []pathSegment{{field: "spec"}, {field: "replicas"}},
+		},
+		{
+			name: "wildcard no meio",
+			path: "spec.template.spec.containers[*].image",
+			want: []pathSegment{
+				{field: "spec"}, {field: "template"}, {field: "spec"},
+				{field: "containers", wildcard: true}, {field: "image"},
+			},
+		},
+		{
+			name:    "indice numérico não suportado",
+			path:    "spec.containers[0].image",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsePath(%q): esperava erro, não houve", tt.path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePath(%q): erro inesperado: %v", tt.path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parsePath(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBuildPatch_MergeContainerFieldsByName cobre o caso que o pacote existe
+// para resolver: reverter só a imagem/recursos dos containers possuídos,
+// preservando um sidecar injetado por webhook que não existe no desired.
+func TestBuildPatch_MergeContainerFieldsByName(t *testing.T) {
+	ownedPaths := []string{
+		"spec.template.spec.containers[*].image",
+		"spec.template.spec.containers[*].resources",
+	}
+
+	desired := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "app:v1"},
+					},
+				},
+			},
+		},
+	}
+	live := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "app:v2"},
+						map[string]interface{}{"name": "sidecar-injetado-por-webhook", "image": "sidecar:v1"},
+					},
+				},
+			},
+		},
+	}
+
+	patch, changed, err := BuildPatch(desired, live, ownedPaths)
+	if err != nil {
+		t.Fatalf("BuildPatch: erro inesperado: %v", err)
+	}
+	if !changed {
+		t.Fatalf("BuildPatch: esperava changed=true (imagem divergente)")
+	}
+
+	containers, _, err := unstructured.NestedSlice(patch, "spec", "template", "spec", "containers")
+	if err != nil {
+		t.Fatalf("erro ao ler containers do patch: %v", err)
+	}
+	if len(containers) != 1 {
+		t.Fatalf("patch deve conter só o container possuído (app), não o sidecar: %+v", containers)
+	}
+	app := containers[0].(map[string]interface{})
+	if app["name"] != "app" || app["image"] != "app:v1" {
+		t.Fatalf("patch do container app incorreto: %+v", app)
+	}
+}
+
+// TestBuildPatch_NoChangeWhenEqual garante que BuildPatch reporta changed=false
+// quando o valor possuído já bate com o observado, mesmo que o patch ainda
+// contenha o campo (idempotência do apply engine).
+func TestBuildPatch_NoChangeWhenEqual(t *testing.T) {
+	ownedPaths := []string{"spec.template.spec.containers[*].image"}
+
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "app:v1"},
+					},
+				},
+			},
+		},
+	}
+
+	_, changed, err := BuildPatch(obj, obj, ownedPaths)
+	if err != nil {
+		t.Fatalf("BuildPatch: erro inesperado: %v", err)
+	}
+	if changed {
+		t.Fatalf("BuildPatch: esperava changed=false quando desired == live")
+	}
+}
+
+// TestBuildPatch_PlainListPathClobbers documenta por que DefaultConfigs não
+// usa "spec.template" como owned path: um caminho plano que cobre uma lista
+// sem patchMergeKey (ex.: tolerations) substitui a lista inteira, perdendo
+// qualquer entrada que exista só no live e não no desired.
+func TestBuildPatch_PlainListPathClobbers(t *testing.T) {
+	desired := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"tolerations": []interface{}{},
+				},
+			},
+		},
+	}
+	live := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"tolerations": []interface{}{
+						map[string]interface{}{"key": "injetada-por-webhook", "operator": "Exists"},
+					},
+				},
+			},
+		},
+	}
+
+	patch, _, err := BuildPatch(desired, live, []string{"spec.template"})
+	if err != nil {
+		t.Fatalf("BuildPatch: erro inesperado: %v", err)
+	}
+
+	tolerations, _, err := unstructured.NestedSlice(patch, "spec", "template", "spec", "tolerations")
+	if err != nil {
+		t.Fatalf("erro ao ler tolerations do patch: %v", err)
+	}
+	if len(tolerations) != 0 {
+		t.Fatalf("um owned path plano largo deveria clobberizar a lista (0 tolerations no patch), mas preservou %+v — se este teste passar a falhar, DefaultConfigs pode voltar a usar caminhos largos com segurança", tolerations)
+	}
+}
@@ -0,0 +1,42 @@
+package reconcile
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// EnsureFinalizer adiciona finalizerName ao objeto e persiste a mudança caso
+// ele ainda não esteja presente. Retorna added=true quando o objeto foi
+// atualizado, sinal para o chamador interromper o Reconcile atual. O update
+// que só adiciona o finalizer é descartado por IgnoreMetadataOnlyUpdates e não
+// gera um novo evento, então o chamador pede o requeue ele mesmo.
+func EnsureFinalizer(ctx context.Context, c client.Client, obj client.Object, finalizerName string) (added bool, err error) {
+	if controllerutil.ContainsFinalizer(obj, finalizerName) {
+		return false, nil
+	}
+	controllerutil.AddFinalizer(obj, finalizerName)
+	if err := c.Update(ctx, obj); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseFinalizer remove finalizerName do objeto e persiste a mudança,
+// caso settled seja true. Use isto na remoção do CR: settled deve refletir
+// se o trabalho em andamento (ex.: um rollback) já convergiu o suficiente
+// para ser seguro deixar o Kubernetes apagar o objeto.
+func ReleaseFinalizer(ctx context.Context, c client.Client, obj client.Object, finalizerName string, settled bool) (released bool, err error) {
+	if !controllerutil.ContainsFinalizer(obj, finalizerName) {
+		return false, nil
+	}
+	if !settled {
+		return false, nil
+	}
+	controllerutil.RemoveFinalizer(obj, finalizerName)
+	if err := c.Update(ctx, obj); err != nil {
+		return false, err
+	}
+	return true, nil
+}
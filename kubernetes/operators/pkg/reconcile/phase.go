@@ -0,0 +1,68 @@
+// Package reconcile reúne o comportamento comum aos reconciliadores do
+// autocura: um pequeno state machine de fases, gestão de finalizer e um
+// helper de patch de status, para que HealingReconciler e RollbackReconciler
+// parem de codificar RequeueAfter fixos e passem a expressar transições de
+// fase declarativas.
+package reconcile
+
+import (
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Phase representa a fase corrente de um CR gerenciado por este pacote.
+type Phase string
+
+const (
+	// PhasePending indica que o recurso ainda não começou a ser processado.
+	PhasePending Phase = "Pending"
+
+	// PhaseInProgress indica que o trabalho está em andamento.
+	PhaseInProgress Phase = "InProgress"
+
+	// PhaseSucceeded indica que o trabalho foi concluído com sucesso.
+	PhaseSucceeded Phase = "Succeeded"
+
+	// PhaseFailed indica que o trabalho falhou.
+	PhaseFailed Phase = "Failed"
+)
+
+const (
+	// inProgressRequeue é o intervalo de poll enquanto o recurso está em
+	// andamento: curto o suficiente para perceber rapidamente a conclusão.
+	inProgressRequeue = 5 * time.Second
+
+	minFailureBackoff = 15 * time.Second
+	maxFailureBackoff = 5 * time.Minute
+)
+
+// ResultForPhase decide o ctrl.Result apropriado para a fase atual de um CR.
+// failureCount só é consultado quando phase é PhaseFailed, e controla um
+// backoff exponencial (limitado a maxFailureBackoff) para não martelar um
+// recurso que está falhando persistentemente. O backoff só faz sentido se o
+// reconciler tratar PhaseFailed como retentável: RollbackReconciler cai de
+// volta no branch de "" /Pending ao reconciliar em PhaseFailed, tentando a
+// reversão de novo a cada expiração do backoff, em vez de deixar a fase
+// falhada reconciliar para sempre sem fazer nada.
+func ResultForPhase(phase Phase, failureCount int32) ctrl.Result {
+	switch phase {
+	case PhaseInProgress:
+		return ctrl.Result{RequeueAfter: inProgressRequeue}
+	case PhaseFailed:
+		return ctrl.Result{RequeueAfter: failureBackoff(failureCount)}
+	default: // PhasePending, PhaseSucceeded, ou qualquer valor desconhecido
+		return ctrl.Result{}
+	}
+}
+
+func failureBackoff(failureCount int32) time.Duration {
+	backoff := minFailureBackoff
+	for i := int32(0); i < failureCount; i++ {
+		backoff *= 2
+		if backoff >= maxFailureBackoff {
+			return maxFailureBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,43 @@
+package reconcile
+
+import (
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// IgnoreMetadataOnlyUpdates retorna um predicate que deixa passar updates em
+// que o spec mudou (via predicate.GenerationChangedPredicate) OU em que o
+// DeletionTimestamp, os labels ou as annotations mudaram. Os demais updates
+// — em que só a lista de finalizers ou o status mudou — são descartados, para
+// que o nosso próprio EnsureFinalizer (e os nossos patches de status) não
+// autodisparem um novo Reconcile.
+func IgnoreMetadataOnlyUpdates() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		nonFinalizerOnlyPredicate{},
+	)
+}
+
+type nonFinalizerOnlyPredicate struct {
+	predicate.Funcs
+}
+
+func (nonFinalizerOnlyPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return true
+	}
+	if !e.ObjectOld.GetDeletionTimestamp().Equal(e.ObjectNew.GetDeletionTimestamp()) {
+		return true
+	}
+	if !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) {
+		return true
+	}
+	if !reflect.DeepEqual(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()) {
+		return true
+	}
+	// labels, annotations e deletionTimestamp iguais: a mudança está na lista
+	// de finalizers ou no status, e ambas são ignoradas.
+	return false
+}
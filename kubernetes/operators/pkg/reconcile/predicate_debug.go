@@ -0,0 +1,36 @@
+package reconcile
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// WithResourceVersionLogging envolve inner para logar, em nível debug, a
+// transição de resourceVersion de cada update observado e se inner decidiu
+// enfileirar um reconcile para ele. Isso é o que torna possível diagnosticar
+// qual evento disparou (ou por que um evento não disparou) um reconcile
+// quando vários predicates estão combinados via predicate.And/Or.
+func WithResourceVersionLogging(name string, inner predicate.Predicate) predicate.Predicate {
+	return loggingPredicate{name: name, inner: inner}
+}
+
+type loggingPredicate struct {
+	name  string
+	inner predicate.Predicate
+}
+
+func (p loggingPredicate) Create(e event.CreateEvent) bool   { return p.inner.Create(e) }
+func (p loggingPredicate) Delete(e event.DeleteEvent) bool   { return p.inner.Delete(e) }
+func (p loggingPredicate) Generic(e event.GenericEvent) bool { return p.inner.Generic(e) }
+
+func (p loggingPredicate) Update(e event.UpdateEvent) bool {
+	enqueued := p.inner.Update(e)
+	log.Log.V(1).Info("predicate de reconcile avaliado",
+		"predicate", p.name,
+		"object", e.ObjectNew.GetName(),
+		"oldResourceVersion", e.ObjectOld.GetResourceVersion(),
+		"newResourceVersion", e.ObjectNew.GetResourceVersion(),
+		"enqueued", enqueued)
+	return enqueued
+}
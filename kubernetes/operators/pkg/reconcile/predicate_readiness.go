@@ -0,0 +1,39 @@
+package reconcile
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// StatefulSetReadyPredicate só deixa passar eventos de criação/atualização de
+// StatefulSet quando status.readyReplicas já bate com spec.replicas; eventos
+// de remoção e genéricos passam sem filtro. HealingReconciler usa isto
+// ao observar o StatefulSet alvo de um rollback em andamento, para só
+// reconciliar quando ele de fato se estabilizou em vez de a cada heartbeat
+// de status intermediário.
+type StatefulSetReadyPredicate struct {
+	predicate.Funcs
+}
+
+func (StatefulSetReadyPredicate) Create(e event.CreateEvent) bool {
+	return statefulSetReady(e.Object)
+}
+
+func (StatefulSetReadyPredicate) Update(e event.UpdateEvent) bool {
+	return statefulSetReady(e.ObjectNew)
+}
+
+func statefulSetReady(obj client.Object) bool {
+	sts, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		// objeto de outro tipo: não é nosso papel filtrar, deixa passar.
+		return true
+	}
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	return sts.Status.ReadyReplicas == desired
+}
@@ -0,0 +1,19 @@
+package reconcile
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PatchStatus aplica mutate ao objeto em memória e persiste apenas o
+// subresource de status via merge patch (client.MergeFrom), em vez de um
+// Status().Update() do objeto inteiro. Isso evita o erro "the object has
+// been modified; please apply your changes to the latest version" quando
+// outro controller alterou o spec/metadata entre o Get original e este
+// patch.
+func PatchStatus(ctx context.Context, c client.Client, obj client.Object, mutate func()) error {
+	original := obj.DeepCopyObject().(client.Object)
+	mutate()
+	return c.Status().Patch(ctx, obj, client.MergeFrom(original))
+}